@@ -0,0 +1,24 @@
+package config
+
+// SSHKeyPath returns the SSH private key path recorded for the active user
+// on hostname, or "" if the account isn't configured to use a specific
+// key. It's set by SetSSHKeyPath and read by `gh auth switch
+// --switch-ssh-key`.
+func (c *AuthConfig) SSHKeyPath(hostname string) (string, error) {
+	user, err := c.ActiveUser(hostname)
+	if err != nil {
+		return "", err
+	}
+
+	keyPath, err := c.cfg.Get([]string{hostname, "users", user, "ssh_key_path"})
+	if err != nil {
+		return "", nil
+	}
+	return keyPath, nil
+}
+
+// SetSSHKeyPath records the SSH private key path to use for user on
+// hostname.
+func (c *AuthConfig) SetSSHKeyPath(hostname, user, keyPath string) error {
+	return c.cfg.Set([]string{hostname, "users", user, "ssh_key_path"}, keyPath)
+}
@@ -0,0 +1,153 @@
+package verification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"cuelang.org/go/cue/cuecontext"
+	"github.com/open-policy-agent/opa/rego"
+)
+
+// evaluatePolicySource loads the document at source.Path (a local file path
+// or an https:// URL) and evaluates it against the JSON-serialized
+// verification results. A Rego policy must produce `data.attestation.allow
+// == true` to pass; a CUE document must unify successfully against the
+// results.
+func evaluatePolicySource(source PolicySource, results []*AttestationProcessingResult) error {
+	raw, err := readPolicyDocument(source.Path)
+	if err != nil {
+		return fmt.Errorf("failed to load policy document %s: %w", source.Path, err)
+	}
+
+	lang := source.Lang
+	if lang == "" {
+		lang = policyLangFromExtension(source.Path)
+	}
+	if lang == "" {
+		return fmt.Errorf("could not infer the language of policy document %s, set PolicySource.Lang", source.Path)
+	}
+
+	input, err := json.Marshal(results)
+	if err != nil {
+		return fmt.Errorf("failed to serialize verification results: %w", err)
+	}
+
+	switch lang {
+	case "rego":
+		return evaluateRegoPolicy(source.Path, raw, input)
+	case "cue":
+		return evaluateCuePolicy(raw, input)
+	default:
+		return fmt.Errorf("unsupported policy language %q", lang)
+	}
+}
+
+func policyLangFromExtension(path string) string {
+	switch filepath.Ext(path) {
+	case ".rego":
+		return "rego"
+	case ".cue":
+		return "cue"
+	default:
+		return ""
+	}
+}
+
+func readPolicyDocument(path string) ([]byte, error) {
+	if strings.HasPrefix(path, "https://") {
+		resp, err := http.Get(path) //nolint:gosec // path is explicitly user-provided
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status %s fetching %s", resp.Status, path)
+		}
+		return io.ReadAll(resp.Body)
+	}
+
+	return os.ReadFile(path)
+}
+
+func evaluateRegoPolicy(path string, policy []byte, input []byte) error {
+	var inputDoc any
+	if err := json.Unmarshal(input, &inputDoc); err != nil {
+		return err
+	}
+
+	query, err := rego.New(
+		rego.Query("data.attestation"),
+		rego.Module(path, string(policy)),
+		rego.Input(inputDoc),
+	).PrepareForEval(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to parse policy document: %w", err)
+	}
+
+	resultSet, err := query.Eval(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to evaluate policy document: %w", err)
+	}
+
+	decision, denyMessages := parseRegoDecision(resultSet)
+	if decision {
+		return nil
+	}
+
+	if len(denyMessages) > 0 {
+		return fmt.Errorf("policy denied verification: %s", strings.Join(denyMessages, "; "))
+	}
+	return fmt.Errorf("policy denied verification: data.attestation.allow was not true")
+}
+
+func parseRegoDecision(resultSet rego.ResultSet) (allow bool, denyMessages []string) {
+	if len(resultSet) == 0 || len(resultSet[0].Expressions) == 0 {
+		return false, nil
+	}
+
+	doc, ok := resultSet[0].Expressions[0].Value.(map[string]any)
+	if !ok {
+		return false, nil
+	}
+
+	if v, ok := doc["allow"].(bool); ok {
+		allow = v
+	}
+
+	if denySet, ok := doc["deny"].([]any); ok {
+		for _, d := range denySet {
+			if s, ok := d.(string); ok {
+				denyMessages = append(denyMessages, s)
+			}
+		}
+	}
+
+	return allow, denyMessages
+}
+
+func evaluateCuePolicy(policy []byte, input []byte) error {
+	ctx := cuecontext.New()
+
+	policyVal := ctx.CompileBytes(policy)
+	if policyVal.Err() != nil {
+		return fmt.Errorf("failed to parse policy document: %w", policyVal.Err())
+	}
+
+	inputVal := ctx.CompileBytes(input)
+	if inputVal.Err() != nil {
+		return fmt.Errorf("failed to encode verification results for policy evaluation: %w", inputVal.Err())
+	}
+
+	unified := policyVal.Unify(inputVal)
+	if err := unified.Validate(); err != nil {
+		return fmt.Errorf("policy denied verification: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,208 @@
+package verification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cli/cli/v2/pkg/cmd/attestation/api"
+	"github.com/cli/cli/v2/pkg/cmd/attestation/artifact"
+	"github.com/cli/cli/v2/pkg/cmd/attestation/artifact/oci"
+	"github.com/cli/cli/v2/pkg/cmd/attestation/io"
+
+	"github.com/sigstore/sigstore-go/pkg/fulcio/certificate"
+	"github.com/sigstore/sigstore-go/pkg/verify"
+)
+
+// NoMatchPolicy controls what Verifier.VerifyArtifact does when no
+// attestations are found for an artifact's digest.
+type NoMatchPolicy int
+
+const (
+	// NoMatchDeny fails verification with ErrNoAttestations, the same
+	// behavior as the `gh attestation verify` command.
+	NoMatchDeny NoMatchPolicy = iota
+	// NoMatchAllow returns success with an empty result set.
+	NoMatchAllow
+	// NoMatchWarn logs a warning via the configured Logger and returns
+	// success with an empty result set.
+	NoMatchWarn
+)
+
+// PolicySource is an external policy document (Rego or CUE) to evaluate
+// against the verification result, in addition to the inline Policy options.
+// Path may be a local file path or an https:// URL.
+type PolicySource struct {
+	Path string
+	// Lang is "rego" or "cue". If empty, it's inferred from Path's extension.
+	Lang string
+}
+
+// Policy describes what a Verifier must check before it considers an
+// artifact's attestations verified.
+type Policy struct {
+	Owner          string
+	Repo           string
+	ProvenanceRepo string
+
+	// DigestAlgorithm is the algorithm used to compute the artifact's
+	// digest. Defaults to sha256.
+	DigestAlgorithm string
+	// BundlePath, if set, verifies against bundles stored on disk instead
+	// of fetching attestations from the GitHub API.
+	BundlePath string
+
+	SAN        string
+	SANRegex   string
+	OIDCIssuer string
+
+	Extensions certificate.Extensions
+
+	PredicateType string
+	Limit         int
+
+	// Sources lists additional policy documents (local file paths or
+	// https:// URLs) to evaluate against the verification result.
+	Sources []PolicySource
+
+	// NoMatch controls behavior when no attestations match the artifact.
+	// The zero value, NoMatchDeny, preserves today's CLI behavior.
+	NoMatch NoMatchPolicy
+}
+
+// LibraryOptions configures a Verifier.
+type LibraryOptions struct {
+	APIClient        api.Client
+	OCIClient        oci.Client
+	SigstoreVerifier SigstoreVerifier
+	Logger           *io.Handler
+}
+
+// Verifier fetches and verifies an artifact's attestations against a Policy.
+// It is the library entry point that `gh attestation verify` is a thin
+// cobra wrapper over; it has no dependency on cobra, terminal output, or
+// any other CLI-specific concern, so downstream Go tools can embed it
+// directly instead of shelling out to `gh`.
+type Verifier struct {
+	opts LibraryOptions
+}
+
+// New creates a Verifier from the given LibraryOptions.
+func New(opts LibraryOptions) (*Verifier, error) {
+	if opts.APIClient == nil {
+		return nil, errors.New("an APIClient is required")
+	}
+	if opts.SigstoreVerifier == nil {
+		return nil, errors.New("a SigstoreVerifier is required")
+	}
+	if opts.Logger == nil {
+		return nil, errors.New("a Logger is required")
+	}
+
+	return &Verifier{opts: opts}, nil
+}
+
+// VerifyArtifact fetches the attestations for the given artifact reference
+// (a file path, or an oci://<image-uri>) and verifies them against policy.
+func (v *Verifier) VerifyArtifact(ctx context.Context, artifactRef string, policy Policy) ([]*AttestationProcessingResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	digestAlg := policy.DigestAlgorithm
+	if digestAlg == "" {
+		digestAlg = "sha256"
+	}
+
+	a, err := artifact.NewDigestedArtifact(v.opts.OCIClient, artifactRef, digestAlg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load digest for %s: %w", artifactRef, err)
+	}
+	v.opts.Logger.Printf("Loaded digest %s for %s\n", a.DigestWithAlg(), a.URL)
+
+	owner, repo := policy.Owner, policy.Repo
+	if policy.ProvenanceRepo != "" {
+		owner, repo = "", policy.ProvenanceRepo
+	}
+
+	c := FetchAttestationsConfig{
+		APIClient:  v.opts.APIClient,
+		BundlePath: policy.BundlePath,
+		Digest:     a.DigestWithAlg(),
+		Limit:      policy.Limit,
+		Owner:      owner,
+		Repo:       repo,
+	}
+
+	attestations, err := GetAttestations(c)
+	if err != nil {
+		if errors.Is(err, api.ErrNoAttestations{}) {
+			return v.resolveNoMatch(policy.NoMatch, err)
+		}
+		return nil, err
+	}
+
+	if policy.PredicateType != "" {
+		attestations = FilterAttestations(policy.PredicateType, attestations)
+		if len(attestations) == 0 {
+			return v.resolveNoMatch(policy.NoMatch, api.ErrNoAttestations{})
+		}
+	}
+
+	// GetAttestations above and SigstoreVerifier.Verify below don't accept a
+	// context themselves, so this is the next point where a cancellation
+	// (e.g. from another artifact's --fail-fast failure) can actually stop
+	// this one before it pays for Sigstore verification.
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	sanMatcher, err := verify.NewSANMatcher(policy.SAN, policy.SANRegex)
+	if err != nil {
+		return nil, err
+	}
+	issuerMatcher, err := verify.NewIssuerMatcher(policy.OIDCIssuer, "")
+	if err != nil {
+		return nil, err
+	}
+	certID, err := verify.NewCertificateIdentity(sanMatcher, issuerMatcher, policy.Extensions)
+	if err != nil {
+		return nil, err
+	}
+
+	builtPolicy := verify.NewPolicy(
+		verify.WithArtifactDigest(a.DigestAlgorithm(), a.Digest()),
+		verify.WithCertificateIdentity(certID),
+	)
+
+	res := v.opts.SigstoreVerifier.Verify(attestations, builtPolicy)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, source := range policy.Sources {
+		if err := evaluatePolicySource(source, res.VerifyResults); err != nil {
+			return nil, err
+		}
+	}
+
+	return res.VerifyResults, nil
+}
+
+// resolveNoMatch applies policy.NoMatch when no attestations were found for
+// the artifact.
+func (v *Verifier) resolveNoMatch(noMatch NoMatchPolicy, noMatchErr error) ([]*AttestationProcessingResult, error) {
+	switch noMatch {
+	case NoMatchAllow:
+		return nil, nil
+	case NoMatchWarn:
+		v.opts.Logger.Println(v.opts.Logger.ColorScheme.Yellow("⚠ No attestations found; continuing due to NoMatchWarn policy"))
+		return nil, nil
+	default:
+		return nil, noMatchErr
+	}
+}
@@ -0,0 +1,266 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"github.com/cli/cli/v2/pkg/cmd/attestation/artifact"
+	"github.com/cli/cli/v2/pkg/cmd/attestation/verification"
+)
+
+// artifactResult captures the outcome of verifying a single artifact
+// reference as part of a (possibly batched) verify invocation.
+type artifactResult struct {
+	ArtifactPath string                                      `json:"artifactPath"`
+	Results      []*verification.AttestationProcessingResult `json:"attestations,omitempty"`
+	Error        string                                      `json:"error,omitempty"`
+}
+
+// resolveArtifactPaths expands any directory arguments into the file paths
+// they contain. Directories are only accepted when recursive is set;
+// oci:// references are passed through unchanged.
+func resolveArtifactPaths(paths []string, recursive bool) ([]string, error) {
+	var resolved []string
+
+	for _, p := range paths {
+		if isOCIArtifactPath(p) {
+			resolved = append(resolved, p)
+			continue
+		}
+
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.IsDir() {
+			resolved = append(resolved, p)
+			continue
+		}
+
+		if !recursive {
+			return nil, errDirectoryWithoutRecursive(p)
+		}
+
+		err = filepath.WalkDir(p, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() {
+				resolved = append(resolved, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return resolved, nil
+}
+
+func isOCIArtifactPath(p string) bool {
+	return len(p) > len(ociPrefix) && p[:len(ociPrefix)] == ociPrefix
+}
+
+const ociPrefix = "oci://"
+
+func errDirectoryWithoutRecursive(dir string) error {
+	return &directoryWithoutRecursiveError{dir: dir}
+}
+
+type directoryWithoutRecursiveError struct {
+	dir string
+}
+
+func (e *directoryWithoutRecursiveError) Error() string {
+	return e.dir + " is a directory; pass --recursive to verify every file it contains"
+}
+
+// digestGroup pairs one unique artifact digest with every path that produced
+// it, so that verification against the GitHub API only happens once per
+// distinct digest even if the same content appears under multiple paths.
+type digestGroup struct {
+	digest string
+	paths  []string
+}
+
+// errFailFast marks a digest group that was never attempted because an
+// earlier failure triggered --fail-fast.
+var errFailFast = errors.New("skipped verifying after an earlier failure (--fail-fast)")
+
+// failFastFailure identifies the one digest group whose failure actually
+// triggered --fail-fast's cancellation, as opposed to every other group
+// that happened to fail (a digest can fail on its own merits after
+// cancellation was already requested, in which case it is not the
+// trigger).
+type failFastFailure struct {
+	artifactPath string
+	err          error
+}
+
+// failFastTracker records the one failure that actually triggers
+// --fail-fast's cancellation; every later call still cancels but is not
+// recorded, even if it also reports a failure (that failure happened on its
+// own merits, not because of the cancellation). It's safe for concurrent use by the worker pool in
+// runBatchVerify.
+type failFastTracker struct {
+	once    sync.Once
+	trigger *failFastFailure
+}
+
+// recordFailure sets the tracker's trigger on its first call only, then
+// invokes cancel every time so that every subsequent failure still tears
+// down the shared context.
+func (t *failFastTracker) recordFailure(artifactPath string, err error, cancel context.CancelFunc) {
+	t.once.Do(func() {
+		t.trigger = &failFastFailure{artifactPath: artifactPath, err: err}
+	})
+	cancel()
+}
+
+// runBatchVerify verifies every artifact in opts.ArtifactPaths concurrently,
+// using a worker pool bounded by GOMAXPROCS, and returns one artifactResult
+// per input path (paths sharing a digest share a result). When opts.FailFast
+// is set, the first failure cancels a context shared by every worker: queued
+// groups that haven't started are skipped outright, and VerifyArtifact
+// itself checks the context between its own stages so it can abort before
+// doing further work, though the underlying GitHub API and Sigstore calls it
+// makes don't yet accept a context and so run to completion once started.
+// The triggering failure is also returned, since it's the one callers should
+// report rather than whichever digest happens to come first in path order.
+func runBatchVerify(opts *Options, v *verification.Verifier, paths []string) ([]artifactResult, *failFastFailure) {
+	groups, pathToDigest, err := groupByDigest(opts, paths)
+	if err != nil {
+		out := make([]artifactResult, len(paths))
+		for i, p := range paths {
+			out[i] = artifactResult{ArtifactPath: p, Error: err.Error()}
+		}
+		return out, nil
+	}
+
+	digestResults := make(map[string][]*verification.AttestationProcessingResult, len(groups))
+	digestErrors := make(map[string]error, len(groups))
+
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(groups) {
+		workers = len(groups)
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var tracker failFastTracker
+	jobs := make(chan digestGroup)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for g := range jobs {
+				if opts.FailFast && ctx.Err() != nil {
+					mu.Lock()
+					digestErrors[g.digest] = errFailFast
+					mu.Unlock()
+					continue
+				}
+
+				results, err := verifyOneArtifact(ctx, opts, v, g.paths[0])
+
+				mu.Lock()
+				if err != nil {
+					digestErrors[g.digest] = err
+					if opts.FailFast {
+						tracker.recordFailure(g.paths[0], err, cancel)
+					}
+				} else {
+					digestResults[g.digest] = results
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, g := range groups {
+		jobs <- g
+	}
+	close(jobs)
+	wg.Wait()
+
+	out := make([]artifactResult, len(paths))
+	for i, p := range paths {
+		digest := pathToDigest[p]
+		if err, ok := digestErrors[digest]; ok {
+			out[i] = artifactResult{ArtifactPath: p, Error: err.Error()}
+			continue
+		}
+		out[i] = artifactResult{ArtifactPath: p, Results: digestResults[digest]}
+	}
+
+	return out, tracker.trigger
+}
+
+// groupByDigest computes the digest of every artifact path up front so that
+// artifacts with identical content only hit the GitHub API once. A path whose
+// digest can't be computed is put in a group of its own, so that it fails
+// when verified without failing every other artifact with it.
+func groupByDigest(opts *Options, paths []string) ([]digestGroup, map[string]string, error) {
+	pathToDigest := make(map[string]string, len(paths))
+	order := make([]string, 0, len(paths))
+	byDigest := make(map[string][]string, len(paths))
+
+	for _, p := range paths {
+		digest, err := digestForArtifact(opts, p)
+		if err != nil {
+			digest = "unresolved:" + p
+		}
+
+		pathToDigest[p] = digest
+		if _, seen := byDigest[digest]; !seen {
+			order = append(order, digest)
+		}
+		byDigest[digest] = append(byDigest[digest], p)
+	}
+
+	groups := make([]digestGroup, 0, len(order))
+	for _, digest := range order {
+		groups = append(groups, digestGroup{digest: digest, paths: byDigest[digest]})
+	}
+
+	return groups, pathToDigest, nil
+}
+
+// digestForArtifact computes the digest of a single artifact reference so
+// that it can be grouped with other references sharing the same content.
+func digestForArtifact(opts *Options, artifactRef string) (string, error) {
+	a, err := artifact.NewDigestedArtifact(opts.OCIClient, artifactRef, opts.DigestAlgorithm)
+	if err != nil {
+		return "", err
+	}
+	return a.DigestWithAlg(), nil
+}
+
+// verifyOneArtifact runs the full verify pipeline (fetch, Sigstore policy,
+// extension gates, and --policy evaluation via Policy.Sources) for a single
+// artifact reference.
+func verifyOneArtifact(ctx context.Context, opts *Options, v *verification.Verifier, artifactRef string) ([]*verification.AttestationProcessingResult, error) {
+	results, err := v.VerifyArtifact(ctx, artifactRef, opts.toPolicy())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := enforceExtensionGates(opts, results); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
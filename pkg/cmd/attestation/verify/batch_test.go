@@ -0,0 +1,59 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestFailFastTrackerRecordsOnlyTheTriggeringFailure(t *testing.T) {
+	_, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var tracker failFastTracker
+	var cancelCount int
+	var mu sync.Mutex
+	countingCancel := func() {
+		mu.Lock()
+		cancelCount++
+		mu.Unlock()
+		cancel()
+	}
+
+	const workers = 20
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tracker.recordFailure("artifact", errors.New("boom"), countingCancel)
+		}(i)
+	}
+	wg.Wait()
+
+	if tracker.trigger == nil {
+		t.Fatal("recordFailure never set a trigger")
+	}
+	if tracker.trigger.artifactPath != "artifact" {
+		t.Errorf("trigger.artifactPath = %q, want %q", tracker.trigger.artifactPath, "artifact")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if cancelCount != workers {
+		t.Errorf("cancel was called %d times, want %d (every failure must still cancel, not just the first)", cancelCount, workers)
+	}
+}
+
+func TestFailFastTrackerKeepsTheFirstFailureOverLaterOnes(t *testing.T) {
+	var tracker failFastTracker
+	noop := func() {}
+
+	tracker.recordFailure("first", errors.New("first error"), noop)
+	tracker.recordFailure("second", errors.New("second error"), noop)
+
+	if tracker.trigger.artifactPath != "first" {
+		t.Errorf("trigger.artifactPath = %q, want %q", tracker.trigger.artifactPath, "first")
+	}
+}
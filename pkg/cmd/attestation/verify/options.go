@@ -0,0 +1,133 @@
+package verify
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/cli/cli/v2/pkg/cmd/attestation/api"
+	"github.com/cli/cli/v2/pkg/cmd/attestation/artifact/oci"
+	"github.com/cli/cli/v2/pkg/cmd/attestation/io"
+	"github.com/cli/cli/v2/pkg/cmd/attestation/verification"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+)
+
+const GitHubOIDCIssuer = "https://token.actions.githubusercontent.com"
+
+// Options captures the options for the verify command
+type Options struct {
+	ArtifactPaths     []string
+	Recursive         bool
+	FailFast          bool
+	BundlePath        string
+	DigestAlgorithm   string
+	Owner             string
+	Repo              string
+	ProvenanceRepo    string
+	PredicateType     string
+	NoPublicGood      bool
+	CustomTrustedRoot string
+	Limit             int
+
+	DenySelfHostedRunner bool
+	SAN                  string
+	SANRegex             string
+	OIDCIssuer           string
+
+	CertGitHubWorkflowRepository string
+	CertGitHubWorkflowTrigger    string
+	CertGitHubWorkflowSHA        string
+	CertGitHubWorkflowName       string
+	CertGitHubWorkflowRef        string
+	SourceRef                    string
+	SourceRefRegex               string
+	SourceBranch                 string
+	SourceTag                    string
+	SourceVersionedTag           string
+	BuildSignerURI               string
+	BuildSignerDigest            string
+
+	PolicyPath string
+	PolicyLang string
+
+	Logger           *io.Handler
+	APIClient        api.Client
+	OCIClient        oci.Client
+	SigstoreVerifier verification.SigstoreVerifier
+
+	exporter cmdutil.Exporter
+}
+
+// AreFlagsValid checks that the provided flag combination is valid before running the command
+func (opts *Options) AreFlagsValid() error {
+	if opts.Owner == "" && opts.Repo == "" {
+		return errors.New("must specify one of --owner or --repo")
+	}
+
+	if opts.ProvenanceRepo != "" {
+		if _, _, err := splitRepoOwner(opts.ProvenanceRepo); err != nil {
+			return fmt.Errorf("invalid value for --provenance-repository: %w", err)
+		}
+	}
+
+	if opts.PolicyLang != "" && opts.PolicyLang != "rego" && opts.PolicyLang != "cue" {
+		return fmt.Errorf("unsupported --policy-lang %q, expected \"rego\" or \"cue\"", opts.PolicyLang)
+	}
+
+	return nil
+}
+
+// Clean cleans up any file path options that may have been provided
+func (opts *Options) Clean() {
+	if opts.BundlePath != "" {
+		opts.BundlePath = filepath.Clean(opts.BundlePath)
+	}
+	if opts.CustomTrustedRoot != "" {
+		opts.CustomTrustedRoot = filepath.Clean(opts.CustomTrustedRoot)
+	}
+}
+
+// SetPolicyFlags derives any policy flag defaults that depend on other
+// flags having already been parsed and validated
+func (opts *Options) SetPolicyFlags() {
+	if opts.SAN == "" && opts.SANRegex == "" {
+		if opts.Repo != "" {
+			opts.SANRegex = fmt.Sprintf(`(?i)^https://github\.com/%s/`, opts.Repo)
+		} else if opts.Owner != "" {
+			opts.SANRegex = fmt.Sprintf(`(?i)^https://github\.com/%s/`, opts.Owner)
+		}
+	}
+}
+
+// toPolicy translates the parsed flags into the verification.Policy consumed
+// by the verification.Verifier library.
+func (opts *Options) toPolicy() verification.Policy {
+	var sources []verification.PolicySource
+	if opts.PolicyPath != "" {
+		sources = append(sources, verification.PolicySource{Path: opts.PolicyPath, Lang: opts.PolicyLang})
+	}
+
+	return verification.Policy{
+		Owner:           opts.Owner,
+		Repo:            opts.Repo,
+		ProvenanceRepo:  opts.ProvenanceRepo,
+		DigestAlgorithm: opts.DigestAlgorithm,
+		BundlePath:      opts.BundlePath,
+		SAN:             opts.SAN,
+		SANRegex:        opts.SANRegex,
+		OIDCIssuer:      opts.OIDCIssuer,
+		Extensions:      buildCertificateExtensions(opts),
+		PredicateType:   opts.PredicateType,
+		Limit:           opts.Limit,
+		Sources:         sources,
+	}
+}
+
+func splitRepoOwner(nwo string) (owner, name string, err error) {
+	owner, name, found := strings.Cut(nwo, "/")
+	if !found || owner == "" || name == "" {
+		return "", "", fmt.Errorf("expected the OWNER/REPO format, got %q", nwo)
+	}
+	return owner, name, nil
+}
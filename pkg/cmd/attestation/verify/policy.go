@@ -0,0 +1,129 @@
+package verify
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cli/cli/v2/pkg/cmd/attestation/verification"
+
+	"github.com/sigstore/sigstore-go/pkg/fulcio/certificate"
+	"golang.org/x/mod/semver"
+)
+
+// buildCertificateExtensions maps the individual `--cert-github-workflow-*` and
+// `--build-signer-*` flags onto the Fulcio certificate extension OIDs that
+// GitHub's issuer populates. Extensions left unset by the user are left
+// zero-valued, so verification does not enforce them.
+func buildCertificateExtensions(opts *Options) certificate.Extensions {
+	sourceRef := opts.SourceRef
+	if sourceRef == "" {
+		sourceRef = opts.CertGitHubWorkflowRef
+	}
+	if opts.SourceBranch != "" {
+		sourceRef = fmt.Sprintf("refs/heads/%s", opts.SourceBranch)
+	} else if opts.SourceTag != "" {
+		sourceRef = fmt.Sprintf("refs/tags/%s", opts.SourceTag)
+	}
+
+	ext := certificate.Extensions{
+		SourceRepositoryRef:    sourceRef,
+		SourceRepositoryDigest: opts.CertGitHubWorkflowSHA,
+		BuildTrigger:           opts.CertGitHubWorkflowTrigger,
+		GithubWorkflowName:     opts.CertGitHubWorkflowName,
+		BuildSignerURI:         opts.BuildSignerURI,
+		BuildSignerDigest:      opts.BuildSignerDigest,
+	}
+
+	// SourceRepositoryURI/SourceRepositoryOwnerURI are always enforced
+	// against --repo/--owner, even when --provenance-repository redirects
+	// where attestations are fetched from: otherwise an attacker who can
+	// publish to the provenance repository could mint attestations that
+	// claim to cover an artifact they don't own. --cert-github-workflow-repository
+	// overrides SourceRepositoryURI when the caller wants to pin an exact
+	// value rather than derive it from --repo.
+	if opts.CertGitHubWorkflowRepository != "" {
+		ext.SourceRepositoryURI = opts.CertGitHubWorkflowRepository
+	} else if opts.Repo != "" {
+		ext.SourceRepositoryURI = fmt.Sprintf("https://github.com/%s", opts.Repo)
+	}
+
+	owner := opts.Owner
+	if owner == "" && opts.Repo != "" {
+		owner, _, _ = splitRepoOwner(opts.Repo)
+	}
+	if owner != "" {
+		ext.SourceRepositoryOwnerURI = fmt.Sprintf("https://github.com/%s", owner)
+	}
+
+	if opts.DenySelfHostedRunner {
+		// GitHub's issuer only ever populates this extension with
+		// "github-hosted" or "self-hosted"; requiring an exact match against
+		// "github-hosted" rejects attestations generated on self-hosted runners.
+		ext.RunnerEnvironment = "github-hosted"
+	}
+
+	return ext
+}
+
+// enforceExtensionGates applies the certificate extension checks that can't be
+// expressed as exact-match PolicyBuilder options, namely the `-regex` variant
+// of `--source-ref` and `--source-versioned-tag`. Every already-verified
+// result must satisfy the gates.
+func enforceExtensionGates(opts *Options, results []*verification.AttestationProcessingResult) error {
+	var sourceRefRegex *regexp.Regexp
+	if opts.SourceRefRegex != "" {
+		var err error
+		sourceRefRegex, err = regexp.Compile(opts.SourceRefRegex)
+		if err != nil {
+			return fmt.Errorf("invalid value for --source-ref-regex: %w", err)
+		}
+	}
+
+	for _, res := range results {
+		ref := res.VerificationResult.Signature.Certificate.Extensions.SourceRepositoryRef
+
+		if sourceRefRegex != nil && !sourceRefRegex.MatchString(ref) {
+			return fmt.Errorf("source repository ref %q does not match --source-ref-regex %q", ref, opts.SourceRefRegex)
+		}
+
+		if opts.SourceVersionedTag != "" {
+			if err := verifyVersionedTag(ref, opts.SourceVersionedTag); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// verifyVersionedTag checks that the attested tag, extracted from the
+// certificate's `refs/tags/<tag>` source repository ref, satisfies the
+// semantic version constraint given to --source-versioned-tag. An attested
+// tag satisfies a constraint if it is greater than or equal to it and has the
+// same major and minor version (a constraint without a minor version counts
+// as minor 0), e.g. attested `v1.2.4` satisfies `v1.2.3`, and attested
+// `v1.3.0` does not.
+func verifyVersionedTag(ref, constraint string) error {
+	tag, ok := strings.CutPrefix(ref, "refs/tags/")
+	if !ok {
+		return fmt.Errorf("source repository ref %q is not a tag, cannot satisfy --source-versioned-tag %q", ref, constraint)
+	}
+
+	if !semver.IsValid(tag) {
+		return fmt.Errorf("attested tag %q is not a valid semantic version", tag)
+	}
+	if !semver.IsValid(constraint) {
+		return fmt.Errorf("invalid value for --source-versioned-tag: %q is not a valid semantic version", constraint)
+	}
+
+	if semver.Compare(semver.MajorMinor(tag), semver.MajorMinor(constraint)) != 0 {
+		return fmt.Errorf("attested tag %q does not satisfy --source-versioned-tag %q", tag, constraint)
+	}
+
+	if semver.Compare(tag, constraint) < 0 {
+		return fmt.Errorf("attested tag %q does not satisfy --source-versioned-tag %q", tag, constraint)
+	}
+
+	return nil
+}
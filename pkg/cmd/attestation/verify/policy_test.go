@@ -0,0 +1,32 @@
+package verify
+
+import "testing"
+
+func TestVerifyVersionedTag(t *testing.T) {
+	tests := []struct {
+		name       string
+		ref        string
+		constraint string
+		wantErr    bool
+	}{
+		{name: "exact match", ref: "refs/tags/v1.2.3", constraint: "v1.2.3", wantErr: false},
+		{name: "patch ahead satisfies", ref: "refs/tags/v1.2.4", constraint: "v1.2.3", wantErr: false},
+		{name: "minor ahead does not satisfy", ref: "refs/tags/v1.3.0", constraint: "v1.2.3", wantErr: true},
+		{name: "patch behind does not satisfy", ref: "refs/tags/v1.2.2", constraint: "v1.2.3", wantErr: true},
+		{name: "not a tag ref", ref: "refs/heads/main", constraint: "v1.2.3", wantErr: true},
+		{name: "invalid attested tag", ref: "refs/tags/not-a-version", constraint: "v1.2.3", wantErr: true},
+		{name: "invalid constraint", ref: "refs/tags/v1.2.3", constraint: "not-a-version", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyVersionedTag(tt.ref, tt.constraint)
+			if tt.wantErr && err == nil {
+				t.Errorf("verifyVersionedTag(%q, %q) = nil, want error", tt.ref, tt.constraint)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("verifyVersionedTag(%q, %q) = %v, want nil", tt.ref, tt.constraint, err)
+			}
+		})
+	}
+}
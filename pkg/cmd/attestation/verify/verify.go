@@ -1,13 +1,10 @@
 package verify
 
 import (
-	"errors"
 	"fmt"
 	"regexp"
 
-	"github.com/cli/cli/v2/internal/text"
 	"github.com/cli/cli/v2/pkg/cmd/attestation/api"
-	"github.com/cli/cli/v2/pkg/cmd/attestation/artifact"
 	"github.com/cli/cli/v2/pkg/cmd/attestation/artifact/oci"
 	"github.com/cli/cli/v2/pkg/cmd/attestation/auth"
 	"github.com/cli/cli/v2/pkg/cmd/attestation/io"
@@ -21,8 +18,8 @@ import (
 func NewVerifyCmd(f *cmdutil.Factory, runF func(*Options) error) *cobra.Command {
 	opts := &Options{}
 	verifyCmd := &cobra.Command{
-		Use:   "verify [<file-path> | oci://<image-uri>] [--owner | --repo]",
-		Args:  cmdutil.ExactArgs(1, "must specify file path or container image URI, as well as one of --owner or --repo"),
+		Use:   "verify [<file-path> | <directory-path> | oci://<image-uri>]... [--owner | --repo]",
+		Args:  cmdutil.MinimumArgs(1, "must specify file path(s), directory path(s), or container image URI(s), as well as one of --owner or --repo"),
 		Short: "Verify an artifact's integrity using attestations",
 		Long: heredoc.Docf(`
 			### NOTE: This feature is currently in beta, and subject to change.
@@ -51,8 +48,11 @@ func NewVerifyCmd(f *cmdutil.Factory, runF func(*Options) error) *cobra.Command
 			the artifact using attestations stored on disk (c.f. the %[1]sdownload%[1]s command),
 			provide a path to the %[1]s--bundle%[1]s flag.
 
-			To see the full results that are generated upon successful verification, i.e.
-			for use with a policy engine, provide the %[1]s--json-result%[1]s flag.
+			To see the full results that are generated upon successful verification, provide
+			the %[1]s--json-result%[1]s flag. To evaluate those results directly, rather than
+			post-processing the JSON output yourself, provide a Rego or CUE document to the
+			%[1]s--policy%[1]s flag; a Rego policy must set %[1]sdata.attestation.allow%[1]s to
+			%[1]strue%[1]s.
 
 			The attestation's certificate's Subject Alternative Name (SAN) identifies the entity
 			responsible for creating the attestation, which most of the time will be a GitHub
@@ -65,6 +65,18 @@ func NewVerifyCmd(f *cmdutil.Factory, runF func(*Options) error) *cobra.Command
 			%[1]s--cert-identity-regex%[1]s flags to specify the reusable workflow's URI.
 
 			For more policy verification options, see the other available flags.
+
+				If the artifact's attestations were published to a different repository than
+				the one that owns the artifact (for example, a centralized release repository
+				or a shared reusable workflow), provide the %[1]s--provenance-repository%[1]s flag
+				with the value of that repository. The %[1]s--repo%[1]s/%[1]s--owner%[1]s flags will
+				still be enforced against the certificate's source repository extension.
+
+				Multiple file paths, directory paths (with %[1]s--recursive%[1]s), and OCI image URIs
+				may be given at once. Verification runs concurrently and results are aggregated into a
+				single table or, with %[1]s--json-result%[1]s, a single JSON array. By default all
+				artifacts are attempted even if some fail, with the command exiting non-zero if any
+				failed; pass %[1]s--fail-fast%[1]s to stop at the first failure instead.
 			`, "`"),
 		Example: heredoc.Doc(`
 			# Verify a local artifact linked with a repository
@@ -75,6 +87,9 @@ func NewVerifyCmd(f *cmdutil.Factory, runF func(*Options) error) *cobra.Command
 
 			# Verify an OCI image using locally stored attestations
 			$ gh attestation verify oci://<image-uri> --owner github --bundle sha256:foo.jsonl
+
+			# Verify every file under a release directory
+			$ gh attestation verify ./dist --recursive --repo github/example
 		`),
 		// PreRunE is used to validate flags before the command is run
 		// If an error is returned, its message will be printed to the terminal
@@ -83,8 +98,8 @@ func NewVerifyCmd(f *cmdutil.Factory, runF func(*Options) error) *cobra.Command
 			// Create a logger for use throughout the verify command
 			opts.Logger = io.NewHandler(f.IOStreams)
 
-			// set the artifact path
-			opts.ArtifactPath = args[0]
+			// set the artifact paths
+			opts.ArtifactPaths = args
 
 			// Check that the given flag combination is valid
 			if err := opts.AreFlagsValid(); err != nil {
@@ -132,6 +147,8 @@ func NewVerifyCmd(f *cmdutil.Factory, runF func(*Options) error) *cobra.Command
 	}
 
 	// general flags
+	verifyCmd.Flags().BoolVarP(&opts.Recursive, "recursive", "r", false, "Verify every file contained in any given directory path")
+	verifyCmd.Flags().BoolVarP(&opts.FailFast, "fail-fast", "", false, "Stop verifying remaining artifacts as soon as one fails")
 	verifyCmd.Flags().StringVarP(&opts.BundlePath, "bundle", "b", "", "Path to bundle on disk, either a single bundle in a JSON file or a JSON lines file with multiple bundles")
 	cmdutil.DisableAuthCheckFlag(verifyCmd.Flags().Lookup("bundle"))
 	cmdutil.StringEnumFlag(verifyCmd, &opts.DigestAlgorithm, "digest-alg", "d", "sha256", []string{"sha256", "sha512"}, "The algorithm used to compute a digest of the artifact")
@@ -139,6 +156,7 @@ func NewVerifyCmd(f *cmdutil.Factory, runF func(*Options) error) *cobra.Command
 	verifyCmd.Flags().StringVarP(&opts.Repo, "repo", "R", "", "Repository name in the format <owner>/<repo>")
 	verifyCmd.MarkFlagsMutuallyExclusive("owner", "repo")
 	verifyCmd.MarkFlagsOneRequired("owner", "repo")
+	verifyCmd.Flags().StringVarP(&opts.ProvenanceRepo, "provenance-repository", "", "", "Fetch attestations from the given <owner>/<repo> instead of the value of --owner/--repo")
 	verifyCmd.Flags().StringVarP(&opts.PredicateType, "predicate-type", "", "", "Filter attestations by provided predicate type")
 	verifyCmd.Flags().BoolVarP(&opts.NoPublicGood, "no-public-good", "", false, "Only verify attestations signed with GitHub's Sigstore instance")
 	verifyCmd.Flags().StringVarP(&opts.CustomTrustedRoot, "custom-trusted-root", "", "", "Path to a custom trustedroot.json file to use for verification")
@@ -150,101 +168,110 @@ func NewVerifyCmd(f *cmdutil.Factory, runF func(*Options) error) *cobra.Command
 	verifyCmd.Flags().StringVarP(&opts.SANRegex, "cert-identity-regex", "i", "", "Enforce that the certificate's subject alternative name matches the provided regex")
 	verifyCmd.MarkFlagsMutuallyExclusive("cert-identity", "cert-identity-regex")
 	verifyCmd.Flags().StringVarP(&opts.OIDCIssuer, "cert-oidc-issuer", "", GitHubOIDCIssuer, "Issuer of the OIDC token")
+	verifyCmd.Flags().StringVarP(&opts.CertGitHubWorkflowRepository, "cert-github-workflow-repository", "", "", "Enforce that the certificate's repository extension matches the provided value exactly")
+	verifyCmd.Flags().StringVarP(&opts.CertGitHubWorkflowTrigger, "cert-github-workflow-trigger", "", "", "Enforce that the certificate's workflow trigger extension matches the provided value exactly")
+	verifyCmd.Flags().StringVarP(&opts.CertGitHubWorkflowSHA, "cert-github-workflow-sha", "", "", "Enforce that the certificate's workflow SHA extension matches the provided value exactly")
+	verifyCmd.Flags().StringVarP(&opts.CertGitHubWorkflowName, "cert-github-workflow-name", "", "", "Enforce that the certificate's workflow name extension matches the provided value exactly")
+	verifyCmd.Flags().StringVarP(&opts.CertGitHubWorkflowRef, "cert-github-workflow-ref", "", "", "Enforce that the certificate's workflow ref extension matches the provided value exactly")
+	verifyCmd.Flags().StringVarP(&opts.SourceRef, "source-ref", "", "", "Enforce that the certificate's source repository ref extension matches the provided value exactly")
+	verifyCmd.Flags().StringVarP(&opts.SourceRefRegex, "source-ref-regex", "", "", "Enforce that the certificate's source repository ref extension matches the provided regex")
+	verifyCmd.Flags().StringVarP(&opts.SourceBranch, "source-branch", "", "", "Enforce that the artifact was built from the provided branch")
+	verifyCmd.Flags().StringVarP(&opts.SourceTag, "source-tag", "", "", "Enforce that the artifact was built from the provided tag")
+	verifyCmd.Flags().StringVarP(&opts.SourceVersionedTag, "source-versioned-tag", "", "", "Enforce that the artifact was built from a tag that satisfies the provided semantic version constraint")
+	verifyCmd.MarkFlagsMutuallyExclusive("cert-github-workflow-ref", "source-ref", "source-ref-regex", "source-branch", "source-tag", "source-versioned-tag")
+	verifyCmd.Flags().StringVarP(&opts.BuildSignerURI, "build-signer-uri", "", "", "Enforce that the certificate's build signer URI extension matches the provided value exactly")
+	verifyCmd.Flags().StringVarP(&opts.BuildSignerDigest, "build-signer-digest", "", "", "Enforce that the certificate's build signer digest extension matches the provided value exactly")
+	verifyCmd.Flags().StringVarP(&opts.PolicyPath, "policy", "", "", "Path or URL to a Rego or CUE policy document to evaluate against the verification result")
+	cmdutil.StringEnumFlag(verifyCmd, &opts.PolicyLang, "policy-lang", "", "", []string{"rego", "cue"}, "Language of the --policy document, inferred from its file extension if not set")
 
 	return verifyCmd
 }
 
+// runVerify is a thin wrapper over the verification.Verifier library: it
+// translates Options into a verification.Policy, delegates the actual
+// fetch-and-verify work for every given artifact, and renders the
+// aggregated result to the terminal.
 func runVerify(opts *Options) error {
-	artifact, err := artifact.NewDigestedArtifact(opts.OCIClient, opts.ArtifactPath, opts.DigestAlgorithm)
+	v, err := verification.New(verification.LibraryOptions{
+		APIClient:        opts.APIClient,
+		OCIClient:        opts.OCIClient,
+		SigstoreVerifier: opts.SigstoreVerifier,
+		Logger:           opts.Logger,
+	})
 	if err != nil {
-		opts.Logger.Printf(opts.Logger.ColorScheme.Red("✗ Loading digest for %s failed\n"), opts.ArtifactPath)
 		return err
 	}
 
-	opts.Logger.Printf("Loaded digest %s for %s\n", artifact.DigestWithAlg(), artifact.URL)
-
-	c := verification.FetchAttestationsConfig{
-		APIClient:  opts.APIClient,
-		BundlePath: opts.BundlePath,
-		Digest:     artifact.DigestWithAlg(),
-		Limit:      opts.Limit,
-		Owner:      opts.Owner,
-		Repo:       opts.Repo,
-	}
-	attestations, err := verification.GetAttestations(c)
+	paths, err := resolveArtifactPaths(opts.ArtifactPaths, opts.Recursive)
 	if err != nil {
-		if ok := errors.Is(err, api.ErrNoAttestations{}); ok {
-			opts.Logger.Printf(opts.Logger.ColorScheme.Red("✗ No attestations found for subject %s\n"), artifact.DigestWithAlg())
-			return err
-		}
-
-		if c.IsBundleProvided() {
-			opts.Logger.Printf(opts.Logger.ColorScheme.Red("✗ Loading attestations from %s failed\n"), artifact.URL)
-		} else {
-			opts.Logger.Println(opts.Logger.ColorScheme.Red("✗ Loading attestations from GitHub API failed"))
-		}
 		return err
 	}
 
-	pluralAttestation := text.Pluralize(len(attestations), "attestation")
-	if c.IsBundleProvided() {
-		opts.Logger.Printf("Loaded %s from %s\n", pluralAttestation, opts.BundlePath)
-	} else {
-		opts.Logger.Printf("Loaded %s from GitHub API\n", pluralAttestation)
+	batch, failFastTrigger := runBatchVerify(opts, v, paths)
+
+	if failFastTrigger != nil {
+		opts.Logger.Printf(opts.Logger.ColorScheme.Red("✗ Verification failed for %s: %s\n"), failFastTrigger.artifactPath, failFastTrigger.err)
+		return failFastTrigger.err
 	}
 
-	// Apply predicate type filter to returned attestations
-	if opts.PredicateType != "" {
-		filteredAttestations := verification.FilterAttestations(opts.PredicateType, attestations)
+	var failed []artifactResult
+	for _, r := range batch {
+		if r.Error != "" {
+			failed = append(failed, r)
+		}
+	}
 
-		if len(filteredAttestations) == 0 {
-			opts.Logger.Printf(opts.Logger.ColorScheme.Red("✗ No attestations found with predicate type: %s\n"), opts.PredicateType)
+	if opts.exporter != nil {
+		if err := opts.exporter.Write(opts.Logger.IO, batch); err != nil {
+			opts.Logger.Println(opts.Logger.ColorScheme.Red("✗ Failed to write JSON output"))
+			return err
+		}
+	} else {
+		if err := printBatchVerifyTable(opts, batch); err != nil {
 			return err
 		}
-
-		attestations = filteredAttestations
 	}
 
-	policy, err := buildVerifyPolicy(opts, *artifact)
-	if err != nil {
-		opts.Logger.Println(opts.Logger.ColorScheme.Red("✗ Failed to build verification policy"))
-		return err
+	for _, r := range failed {
+		opts.Logger.Printf(opts.Logger.ColorScheme.Red("✗ Verification failed for %s: %s\n"), r.ArtifactPath, r.Error)
 	}
 
-	sigstoreRes := opts.SigstoreVerifier.Verify(attestations, policy)
-	if sigstoreRes.Error != nil {
-		opts.Logger.Println(opts.Logger.ColorScheme.Red("✗ Verification failed"))
-		return sigstoreRes.Error
+	if len(failed) > 0 {
+		return fmt.Errorf("verification failed for %d of %d artifacts", len(failed), len(batch))
 	}
 
-	opts.Logger.Println(opts.Logger.ColorScheme.Green("✓ Verification succeeded!\n"))
+	opts.Logger.Println(opts.Logger.ColorScheme.Green("✓ Verification succeeded!"))
 
-	// If an exporter is provided with the --json flag, write the results to the terminal in JSON format
-	if opts.exporter != nil {
-		// print the results to the terminal as an array of JSON objects
-		if err = opts.exporter.Write(opts.Logger.IO, sigstoreRes.VerifyResults); err != nil {
-			opts.Logger.Println(opts.Logger.ColorScheme.Red("✗ Failed to write JSON output"))
-			return err
+	return nil
+}
+
+// printBatchVerifyTable renders every successfully verified attestation
+// across all artifacts in a single table, with each row annotated with the
+// artifact filename it belongs to.
+func printBatchVerifyTable(opts *Options, batch []artifactResult) error {
+	var tableContent [][]string
+	for _, r := range batch {
+		if r.Error != "" {
+			continue
 		}
-		return nil
-	}
 
-	opts.Logger.Printf("%s was attested by:\n", artifact.DigestWithAlg())
+		rows, err := buildTableVerifyContent(r.Results)
+		if err != nil {
+			opts.Logger.Println(opts.Logger.ColorScheme.Red("failed to parse results"))
+			return err
+		}
 
-	// Otherwise print the results to the terminal in a table
-	tableContent, err := buildTableVerifyContent(sigstoreRes.VerifyResults)
-	if err != nil {
-		opts.Logger.Println(opts.Logger.ColorScheme.Red("failed to parse results"))
-		return err
+		for _, row := range rows {
+			tableContent = append(tableContent, append([]string{r.ArtifactPath}, row...))
+		}
 	}
 
-	headers := []string{"repo", "predicate_type", "workflow"}
-	if err = opts.Logger.PrintTable(headers, tableContent); err != nil {
+	headers := []string{"artifact", "repo", "predicate_type", "workflow"}
+	if err := opts.Logger.PrintTable(headers, tableContent); err != nil {
 		opts.Logger.Println(opts.Logger.ColorScheme.Red("failed to print attestation details to table"))
 		return err
 	}
 
-	// All attestations passed verification and policy evaluation
 	return nil
 }
 
@@ -0,0 +1,15 @@
+package login
+
+import "github.com/cli/cli/v2/internal/config"
+
+// PersistGeneratedSSHKeyPath records keyPath as the SSH identity for
+// user on hostname, once login has generated or uploaded it. It must be
+// called from the SSH key generation step of the login flow itself (the
+// step that runs ssh-keygen and/or uploads the public key to the
+// account) right after that step succeeds, so that `gh auth switch
+// --switch-ssh-key` has something to read back for this account. Without
+// this call, ssh_key_path is never populated and --switch-ssh-key always
+// finds no key on file.
+func PersistGeneratedSSHKeyPath(authCfg *config.AuthConfig, hostname, username, keyPath string) error {
+	return authCfg.SetSSHKeyPath(hostname, username, keyPath)
+}
@@ -0,0 +1,130 @@
+package shared
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cli/cli/v2/git"
+)
+
+// GitCredentialFlow applies the git configuration changes that `gh auth
+// switch` needs to make an account switch actually take effect: the local
+// committer identity, the SSH identity used to reach a host, and the
+// credential helper git invokes for HTTPS remotes.
+type GitCredentialFlow struct {
+	Executable string
+	GitClient  *git.Client
+
+	// UseSSHConfigDir switches SwitchSSHIdentity to manage a Host block in
+	// ~/.ssh/config instead of the repo-local core.sshCommand.
+	UseSSHConfigDir bool
+}
+
+// SwitchLocalGitUsernameAndEmail sets user.name and user.email in the
+// current repository's local git config.
+func (f *GitCredentialFlow) SwitchLocalGitUsernameAndEmail(email, username string) error {
+	if err := f.GitClient.SetConfig("user.email", email); err != nil {
+		return err
+	}
+	return f.GitClient.SetConfig("user.name", username)
+}
+
+// SwitchGlobalGitUsernameAndEmail sets user.name and user.email in the
+// user's global git config.
+func (f *GitCredentialFlow) SwitchGlobalGitUsernameAndEmail(email, username string) error {
+	if err := f.GitClient.SetGlobalConfig("user.email", email); err != nil {
+		return err
+	}
+	return f.GitClient.SetGlobalConfig("user.name", username)
+}
+
+// sshConfigBlockBegin and sshConfigBlockEnd bound the block SwitchSSHIdentity
+// manages for a given host in ~/.ssh/config, so repeated switches replace
+// only what gh wrote and leave the rest of the file untouched.
+const (
+	sshConfigBlockBegin = "# >>> gh auth switch managed block for %s >>>"
+	sshConfigBlockEnd   = "# <<< gh auth switch managed block for %s <<<"
+)
+
+// SwitchSSHIdentity points git's SSH invocation for host at keyPath. By
+// default this rewrites the repo-local core.sshCommand; when
+// UseSSHConfigDir is set it instead rewrites a managed Host block for host
+// in ~/.ssh/config, which takes effect for every repository rather than
+// just the current one.
+func (f *GitCredentialFlow) SwitchSSHIdentity(host, keyPath string) error {
+	if f.UseSSHConfigDir {
+		return f.writeSSHConfigBlock(host, keyPath)
+	}
+
+	sshCommand := fmt.Sprintf("ssh -i %s -o IdentitiesOnly=yes", keyPath)
+	return f.GitClient.SetConfig("core.sshCommand", sshCommand)
+}
+
+func (f *GitCredentialFlow) writeSSHConfigBlock(host, keyPath string) error {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(home, ".ssh", "config")
+
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	block := fmt.Sprintf("%s\nHost %s\n\tIdentityFile %s\n\tIdentitiesOnly yes\n%s\n",
+		fmt.Sprintf(sshConfigBlockBegin, host), host, keyPath, fmt.Sprintf(sshConfigBlockEnd, host))
+
+	updated := replaceManagedSSHBlock(string(existing), host, block)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(updated), 0600)
+}
+
+// replaceManagedSSHBlock replaces the managed block for host within
+// content, or appends it if content has none yet.
+func replaceManagedSSHBlock(content, host, block string) string {
+	begin := fmt.Sprintf(sshConfigBlockBegin, host)
+	end := fmt.Sprintf(sshConfigBlockEnd, host)
+
+	start := strings.Index(content, begin)
+	if start == -1 {
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		return content + block
+	}
+
+	stop := strings.Index(content[start:], end)
+	if stop == -1 {
+		return content + block
+	}
+	stop = start + stop + len(end)
+	if stop < len(content) && content[stop] == '\n' {
+		stop++
+	}
+
+	return content[:start] + block + content[stop:]
+}
+
+// ConfigureCredentialHelper points git's credential helper for host at `gh
+// auth git-credential`, so that an HTTPS git operation against host picks
+// up whichever account is currently active. Any helper previously
+// configured for host is cleared first, so a credential cached by the
+// account being switched away from isn't consulted ahead of gh's.
+func (f *GitCredentialFlow) ConfigureCredentialHelper(host string, global bool) error {
+	key := fmt.Sprintf("credential.https://%s.helper", host)
+	helper := fmt.Sprintf("!%s auth git-credential", f.Executable)
+
+	if global {
+		_ = f.GitClient.UnsetGlobalConfig(key)
+		return f.GitClient.SetGlobalConfig(key, helper)
+	}
+
+	_ = f.GitClient.UnsetConfig(key)
+	return f.GitClient.SetConfig(key, helper)
+}
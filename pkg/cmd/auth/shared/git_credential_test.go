@@ -0,0 +1,53 @@
+package shared
+
+import "testing"
+
+func TestReplaceManagedSSHBlock(t *testing.T) {
+	block := "# >>> gh auth switch managed block for github.com >>>\nHost github.com\n\tIdentityFile ~/.ssh/work\n\tIdentitiesOnly yes\n# <<< gh auth switch managed block for github.com <<<\n"
+
+	tests := []struct {
+		name    string
+		content string
+		host    string
+		block   string
+		want    string
+	}{
+		{
+			name:    "empty file appends the block",
+			content: "",
+			host:    "github.com",
+			block:   block,
+			want:    block,
+		},
+		{
+			name:    "existing content without a trailing newline gets one before the block is appended",
+			content: "Host example.com\n\tUser git",
+			host:    "github.com",
+			block:   block,
+			want:    "Host example.com\n\tUser git\n" + block,
+		},
+		{
+			name:    "an existing managed block for another host is left untouched and the new one is appended",
+			content: "# >>> gh auth switch managed block for enterprise.internal >>>\nHost enterprise.internal\n\tIdentityFile ~/.ssh/ent\n# <<< gh auth switch managed block for enterprise.internal <<<\n",
+			host:    "github.com",
+			block:   block,
+			want:    "# >>> gh auth switch managed block for enterprise.internal >>>\nHost enterprise.internal\n\tIdentityFile ~/.ssh/ent\n# <<< gh auth switch managed block for enterprise.internal <<<\n" + block,
+		},
+		{
+			name:    "an existing managed block for the same host is replaced in place",
+			content: "Host example.com\n\tUser git\n" + block + "Host other.example.com\n\tUser git\n",
+			host:    "github.com",
+			block:   "# >>> gh auth switch managed block for github.com >>>\nHost github.com\n\tIdentityFile ~/.ssh/personal\n\tIdentitiesOnly yes\n# <<< gh auth switch managed block for github.com <<<\n",
+			want:    "Host example.com\n\tUser git\n# >>> gh auth switch managed block for github.com >>>\nHost github.com\n\tIdentityFile ~/.ssh/personal\n\tIdentitiesOnly yes\n# <<< gh auth switch managed block for github.com <<<\nHost other.example.com\n\tUser git\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := replaceManagedSSHBlock(tt.content, tt.host, tt.block)
+			if got != tt.want {
+				t.Errorf("replaceManagedSSHBlock(%q, %q, ...) = %q, want %q", tt.content, tt.host, got, tt.want)
+			}
+		})
+	}
+}
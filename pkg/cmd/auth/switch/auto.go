@@ -0,0 +1,257 @@
+package authswitch
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/cli/cli/v2/git"
+	"github.com/cli/cli/v2/internal/config"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// AutoSwitchMatch describes the context a rule matches against: the repo's
+// directory path prefix and/or its `origin` remote URL.
+type AutoSwitchMatch struct {
+	Host        string `yaml:"host,omitempty"`
+	PathPrefix  string `yaml:"path_prefix,omitempty"`
+	RemoteRegex string `yaml:"remote_regex,omitempty"`
+}
+
+// AutoSwitchTarget is the account a matching rule should switch to.
+type AutoSwitchTarget struct {
+	Hostname string `yaml:"hostname"`
+	User     string `yaml:"user"`
+}
+
+// AutoSwitchRule pairs a match with the account to switch to when it applies.
+// Rules are stored in order and the first match wins.
+type AutoSwitchRule struct {
+	Match  AutoSwitchMatch  `yaml:"match"`
+	Target AutoSwitchTarget `yaml:"target"`
+}
+
+// autoSwitchConfigKey is the top-level config.yml key that stores the
+// rules used by `gh auth switch --auto`, the same way `gh config get/set`
+// reads and writes any other top-level key (hostname "" addresses the
+// top level rather than a per-host value). The rules themselves are a
+// list of structured records rather than a scalar, so the value stored
+// under this key is itself YAML-encoded.
+const autoSwitchConfigKey = "auto_switch"
+
+// LoadAutoSwitchRules reads the rules configured with `gh auth switch auto
+// add` from the auto_switch section of config.yml, returning an empty
+// slice if none have been configured yet. Other commands can call this
+// directly to find the account a directory or remote would resolve to,
+// without shelling out to `gh auth switch --auto`.
+func LoadAutoSwitchRules(cfg config.Config) ([]AutoSwitchRule, error) {
+	raw, err := cfg.Get("", autoSwitchConfigKey)
+	if err != nil || raw == "" {
+		return nil, nil
+	}
+
+	var rules []AutoSwitchRule
+	if err := yaml.Unmarshal([]byte(raw), &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse %s config value: %w", autoSwitchConfigKey, err)
+	}
+
+	return rules, nil
+}
+
+// SaveAutoSwitchRules persists the full set of auto-switch rules under the
+// auto_switch key in config.yml, replacing whatever was previously
+// configured.
+func SaveAutoSwitchRules(cfg config.Config, rules []AutoSwitchRule) error {
+	data, err := yaml.Marshal(rules)
+	if err != nil {
+		return err
+	}
+
+	if err := cfg.Set("", autoSwitchConfigKey, string(data)); err != nil {
+		return err
+	}
+
+	return cfg.Write()
+}
+
+// ResolveAutoSwitchTarget inspects the current directory's git remote and
+// path to find the first auto_switch rule that applies, returning nil if
+// none match. Exported so other commands can reuse the same lookup that
+// `gh auth switch --auto` uses, the same pattern
+// ResolvePinnedAccount follows for pinned accounts.
+func ResolveAutoSwitchTarget(gitClient *git.Client, rules []AutoSwitchRule) (*AutoSwitchTarget, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	var remoteURL, remoteHost string
+	if remotes, err := gitClient.Remotes(); err == nil {
+		if origin := remotes.ResolvedRemote("origin"); origin != nil {
+			remoteURL = origin.FetchURL.String()
+			remoteHost = origin.FetchURL.Host
+		}
+	}
+
+	for _, rule := range rules {
+		if !matchesRule(rule.Match, wd, remoteURL, remoteHost) {
+			continue
+		}
+		target := rule.Target
+		return &target, nil
+	}
+
+	return nil, nil
+}
+
+func matchesRule(m AutoSwitchMatch, wd, remoteURL, remoteHost string) bool {
+	if m.Host != "" && !strings.EqualFold(m.Host, remoteHost) {
+		return false
+	}
+
+	if m.PathPrefix != "" {
+		rel, err := filepath.Rel(m.PathPrefix, wd)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			return false
+		}
+	}
+
+	if m.RemoteRegex != "" {
+		if remoteURL == "" {
+			return false
+		}
+		re, err := regexp.Compile(m.RemoteRegex)
+		if err != nil || !re.MatchString(remoteURL) {
+			return false
+		}
+	}
+
+	return m.Host != "" || m.PathPrefix != "" || m.RemoteRegex != ""
+}
+
+func newCmdSwitchAuto(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "auto",
+		Short: "Manage rules for `gh auth switch --auto`",
+		Long: heredoc.Doc(`
+			Manage the rules used by gh auth switch --auto to pick an account
+			based on the current directory's path or git remote.
+		`),
+	}
+
+	cmd.AddCommand(newCmdSwitchAutoAdd(f))
+	cmd.AddCommand(newCmdSwitchAutoList(f))
+	cmd.AddCommand(newCmdSwitchAutoRemove(f))
+
+	return cmd
+}
+
+func newCmdSwitchAutoAdd(f *cmdutil.Factory) *cobra.Command {
+	var match AutoSwitchMatch
+	var target AutoSwitchTarget
+
+	cmd := &cobra.Command{
+		Use:   "add --hostname <host> --user <user> [--path-prefix <path>] [--remote-regex <regex>]",
+		Short: "Add a gh auth switch --auto rule",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if match.Host == "" && match.PathPrefix == "" && match.RemoteRegex == "" {
+				return fmt.Errorf("at least one of --host, --path-prefix, or --remote-regex is required")
+			}
+
+			cfg, err := f.Config()
+			if err != nil {
+				return err
+			}
+
+			rules, err := LoadAutoSwitchRules(cfg)
+			if err != nil {
+				return err
+			}
+
+			rules = append(rules, AutoSwitchRule{Match: match, Target: target})
+
+			if err := SaveAutoSwitchRules(cfg, rules); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(f.IOStreams.Out, "Added auto-switch rule for %s (%s)\n", target.User, target.Hostname)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&match.Host, "host", "", "Match when the origin remote's host equals this value")
+	cmd.Flags().StringVar(&match.PathPrefix, "path-prefix", "", "Match when the current directory is under this path")
+	cmd.Flags().StringVar(&match.RemoteRegex, "remote-regex", "", "Match when the origin remote URL matches this regex")
+	cmd.Flags().StringVar(&target.Hostname, "hostname", "", "The hostname to switch to")
+	cmd.Flags().StringVar(&target.User, "user", "", "The account to switch to")
+	_ = cmd.MarkFlagRequired("hostname")
+	_ = cmd.MarkFlagRequired("user")
+
+	return cmd
+}
+
+func newCmdSwitchAutoList(f *cmdutil.Factory) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List gh auth switch --auto rules",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := f.Config()
+			if err != nil {
+				return err
+			}
+
+			rules, err := LoadAutoSwitchRules(cfg)
+			if err != nil {
+				return err
+			}
+
+			if len(rules) == 0 {
+				fmt.Fprintln(f.IOStreams.Out, "no auto-switch rules configured")
+				return nil
+			}
+
+			for i, r := range rules {
+				fmt.Fprintf(f.IOStreams.Out, "%d: host=%q path_prefix=%q remote_regex=%q -> %s (%s)\n",
+					i, r.Match.Host, r.Match.PathPrefix, r.Match.RemoteRegex, r.Target.User, r.Target.Hostname)
+			}
+			return nil
+		},
+	}
+}
+
+func newCmdSwitchAutoRemove(f *cmdutil.Factory) *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove <index>",
+		Short: "Remove a gh auth switch --auto rule by its list index",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var index int
+			if _, err := fmt.Sscanf(args[0], "%d", &index); err != nil {
+				return fmt.Errorf("invalid index %q", args[0])
+			}
+
+			cfg, err := f.Config()
+			if err != nil {
+				return err
+			}
+
+			rules, err := LoadAutoSwitchRules(cfg)
+			if err != nil {
+				return err
+			}
+
+			if index < 0 || index >= len(rules) {
+				return fmt.Errorf("no rule at index %d", index)
+			}
+
+			rules = append(rules[:index], rules[index+1:]...)
+
+			return SaveAutoSwitchRules(cfg, rules)
+		},
+	}
+}
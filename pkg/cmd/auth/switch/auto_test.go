@@ -0,0 +1,88 @@
+package authswitch
+
+import "testing"
+
+func TestMatchesRule(t *testing.T) {
+	tests := []struct {
+		name       string
+		match      AutoSwitchMatch
+		wd         string
+		remoteURL  string
+		remoteHost string
+		want       bool
+	}{
+		{
+			name:  "empty match never applies",
+			match: AutoSwitchMatch{},
+			wd:    "/home/monalisa/src/project",
+			want:  false,
+		},
+		{
+			name:       "host matches case-insensitively",
+			match:      AutoSwitchMatch{Host: "GitHub.com"},
+			remoteHost: "github.com",
+			want:       true,
+		},
+		{
+			name:       "host mismatch",
+			match:      AutoSwitchMatch{Host: "github.com"},
+			remoteHost: "enterprise.internal",
+			want:       false,
+		},
+		{
+			name:  "path prefix matches a subdirectory",
+			match: AutoSwitchMatch{PathPrefix: "/home/monalisa/work"},
+			wd:    "/home/monalisa/work/project",
+			want:  true,
+		},
+		{
+			name:  "path prefix does not match a sibling directory",
+			match: AutoSwitchMatch{PathPrefix: "/home/monalisa/work"},
+			wd:    "/home/monalisa/personal/project",
+			want:  false,
+		},
+		{
+			name:      "remote regex matches",
+			match:     AutoSwitchMatch{RemoteRegex: `^https://github\.com/acme/`},
+			remoteURL: "https://github.com/acme/widgets.git",
+			want:      true,
+		},
+		{
+			name:      "remote regex does not match",
+			match:     AutoSwitchMatch{RemoteRegex: `^https://github\.com/acme/`},
+			remoteURL: "https://github.com/other/widgets.git",
+			want:      false,
+		},
+		{
+			name:  "remote regex with no remote URL never matches",
+			match: AutoSwitchMatch{RemoteRegex: `.*`},
+			want:  false,
+		},
+		{
+			name:       "all three conditions must hold",
+			match:      AutoSwitchMatch{Host: "github.com", PathPrefix: "/home/monalisa/work", RemoteRegex: `^https://github\.com/acme/`},
+			wd:         "/home/monalisa/work/project",
+			remoteURL:  "https://github.com/acme/widgets.git",
+			remoteHost: "github.com",
+			want:       true,
+		},
+		{
+			name:       "one mismatched condition fails the rule",
+			match:      AutoSwitchMatch{Host: "github.com", PathPrefix: "/home/monalisa/work", RemoteRegex: `^https://github\.com/acme/`},
+			wd:         "/home/monalisa/personal/project",
+			remoteURL:  "https://github.com/acme/widgets.git",
+			remoteHost: "github.com",
+			want:       false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := matchesRule(tt.match, tt.wd, tt.remoteURL, tt.remoteHost)
+			if got != tt.want {
+				t.Errorf("matchesRule(%+v, %q, %q, %q) = %v, want %v",
+					tt.match, tt.wd, tt.remoteURL, tt.remoteHost, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package authswitch
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/cli/cli/v2/git"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// pinFileName is the path, relative to the repo root, that pins this
+// checkout to a specific account.
+const pinFileName = ".gh/account"
+
+// pinGitConfigKey is the git config fallback for repositories that don't
+// want to commit a .gh/account file.
+const pinGitConfigKey = "gh.account"
+
+// PinnedAccount is the {hostname, user} pair a repository is pinned to.
+type PinnedAccount struct {
+	Hostname string `yaml:"hostname"`
+	User     string `yaml:"user"`
+}
+
+// ResolvePinnedAccount looks up the account the current repository is
+// pinned to, checking .gh/account first and falling back to the
+// gh.account git config value. It returns a nil PinnedAccount if the
+// repository has no pin configured, which is not an error.
+func ResolvePinnedAccount(gitClient *git.Client) (*PinnedAccount, error) {
+	if pin, err := pinFromFile(gitClient); err != nil {
+		return nil, err
+	} else if pin != nil {
+		return pin, nil
+	}
+
+	return pinFromGitConfig(gitClient)
+}
+
+func pinFromFile(gitClient *git.Client) (*PinnedAccount, error) {
+	root, err := gitClient.ToplevelDir()
+	if err != nil {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(root, pinFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var pin PinnedAccount
+	if err := yaml.Unmarshal(data, &pin); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", pinFileName, err)
+	}
+	if pin.Hostname == "" || pin.User == "" {
+		return nil, fmt.Errorf("%s must set both hostname and user", pinFileName)
+	}
+
+	return &pin, nil
+}
+
+func pinFromGitConfig(gitClient *git.Client) (*PinnedAccount, error) {
+	value, err := gitClient.Config(pinGitConfigKey)
+	if err != nil || value == "" {
+		return nil, nil
+	}
+
+	return parsePinConfigValue(value)
+}
+
+// parsePinConfigValue parses the HOSTNAME/USER format stored in the
+// gh.account git config value.
+func parsePinConfigValue(value string) (*PinnedAccount, error) {
+	hostname, user, found := strings.Cut(value, "/")
+	if !found || hostname == "" || user == "" {
+		return nil, fmt.Errorf("invalid %s value %q, expected HOSTNAME/USER", pinGitConfigKey, value)
+	}
+
+	return &PinnedAccount{Hostname: hostname, User: user}, nil
+}
+
+func newCmdSwitchPin(f *cmdutil.Factory) *cobra.Command {
+	var hostname string
+
+	cmd := &cobra.Command{
+		Use:   "pin <user>",
+		Short: "Pin this repository to a specific account",
+		Long: heredoc.Doc(`
+			Pin the current repository to a specific account by writing the
+			gh.account git config value. Once pinned, gh auth switch --respect-pin
+			switches to this account automatically, and other commands can check
+			the pin with authswitch.ResolvePinnedAccount.
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := f.Config()
+			if err != nil {
+				return err
+			}
+
+			if hostname == "" {
+				hosts := cfg.Authentication().Hosts()
+				if len(hosts) != 1 {
+					return fmt.Errorf("must specify --hostname when more than one host is authenticated")
+				}
+				hostname = hosts[0]
+			}
+
+			return f.GitClient.SetConfig(pinGitConfigKey, fmt.Sprintf("%s/%s", hostname, args[0]))
+		},
+	}
+
+	cmd.Flags().StringVar(&hostname, "hostname", "", "The hostname of the account to pin to")
+
+	return cmd
+}
+
+func newCmdSwitchUnpin(f *cmdutil.Factory) *cobra.Command {
+	return &cobra.Command{
+		Use:   "unpin",
+		Short: "Remove this repository's pinned account",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return f.GitClient.UnsetConfig(pinGitConfigKey)
+		},
+	}
+}
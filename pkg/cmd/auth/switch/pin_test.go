@@ -0,0 +1,56 @@
+package authswitch
+
+import "testing"
+
+func TestParsePinConfigValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantPin *PinnedAccount
+		wantErr bool
+	}{
+		{
+			name:    "valid hostname and user",
+			value:   "github.com/monalisa",
+			wantPin: &PinnedAccount{Hostname: "github.com", User: "monalisa"},
+		},
+		{
+			name:    "enterprise hostname",
+			value:   "enterprise.internal/hubot",
+			wantPin: &PinnedAccount{Hostname: "enterprise.internal", User: "hubot"},
+		},
+		{
+			name:    "missing separator",
+			value:   "github.com",
+			wantErr: true,
+		},
+		{
+			name:    "missing user",
+			value:   "github.com/",
+			wantErr: true,
+		},
+		{
+			name:    "missing hostname",
+			value:   "/monalisa",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pin, err := parsePinConfigValue(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parsePinConfigValue(%q) = %+v, nil, want error", tt.value, pin)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parsePinConfigValue(%q) returned unexpected error: %v", tt.value, err)
+			}
+			if *pin != *tt.wantPin {
+				t.Errorf("parsePinConfigValue(%q) = %+v, want %+v", tt.value, pin, tt.wantPin)
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/MakeNowJust/heredoc"
 	"github.com/cli/cli/v2/api"
+	"github.com/cli/cli/v2/git"
 	"github.com/cli/cli/v2/internal/config"
 	"github.com/cli/cli/v2/pkg/cmd/auth/shared"
 	"github.com/cli/cli/v2/pkg/cmdutil"
@@ -18,18 +19,27 @@ import (
 type gitConfigurator interface {
 	SwitchLocalGitUsernameAndEmail(email, username string) error
 	SwitchGlobalGitUsernameAndEmail(email, username string) error
+	SwitchSSHIdentity(host, keyPath string) error
+	ConfigureCredentialHelper(host string, global bool) error
 }
 
 type SwitchOptions struct {
-	IO                    *iostreams.IOStreams
-	Config                func() (config.Config, error)
-	HttpClient            func() (*http.Client, error)
-	Prompter              shared.Prompt
-	Hostname              string
-	Username              string
-	SwitchGitLocalConfig  bool
-	SwitchGitGlobalConfig bool
-	gitConfigure          gitConfigurator
+	IO                      *iostreams.IOStreams
+	Config                  func() (config.Config, error)
+	HttpClient              func() (*http.Client, error)
+	Prompter                shared.Prompt
+	GitClient               *git.Client
+	Hostname                string
+	Username                string
+	SwitchGitLocalConfig    bool
+	SwitchGitGlobalConfig   bool
+	SwitchSSHKey            bool
+	GitSSHConfig            bool
+	SetupGitCredential      bool
+	SetupGitCredentialLocal bool
+	Auto                    bool
+	RespectPin              bool
+	gitConfigure            gitConfigurator
 }
 
 func NewCmdSwitch(f *cmdutil.Factory, runF func(*SwitchOptions) error) *cobra.Command {
@@ -38,6 +48,7 @@ func NewCmdSwitch(f *cmdutil.Factory, runF func(*SwitchOptions) error) *cobra.Co
 		Config:     f.Config,
 		Prompter:   f.Prompter,
 		HttpClient: f.HttpClient,
+		GitClient:  f.GitClient,
 	}
 
 	cmd := &cobra.Command{
@@ -55,6 +66,27 @@ func NewCmdSwitch(f *cmdutil.Factory, runF func(*SwitchOptions) error) *cobra.Co
 			required either through the %[1]s--user%[1]s flag or an interactive prompt.
 
 			For a list of authenticated accounts you can run %[1]sgh auth status%[1]s.
+
+			Passing %[1]s--auto%[1]s switches to the account matched by the current
+			directory's path or git remote, using rules configured with
+			%[1]sgh auth switch auto add%[1]s.
+
+			Passing %[1]s--switch-ssh-key%[1]s additionally rewrites the SSH identity
+			used for the target account, if one was configured during %[1]sgh auth
+			login%[1]s. By default this sets the repo-local %[1]score.sshCommand%[1]s;
+			pass %[1]s--git-ssh-config%[1]s to update the managed block for this host
+			in %[1]s~/.ssh/config%[1]s instead.
+
+			Passing %[1]s--setup-git-credential%[1]s re-points git's credential helper
+			at the target account for this host in the global git config, equivalent
+			to running %[1]sgh auth setup-git%[1]s scoped to the hostname just switched
+			to. Pass %[1]s--setup-git-credential-local%[1]s to scope it to the current
+			repository instead.
+
+			If the current repository has a pinned account, configured with %[1]sgh
+			auth switch pin%[1]s, passing %[1]s--respect-pin%[1]s switches to it
+			automatically; otherwise a mismatch between the pin and the active
+			account is reported as a warning.
 		`, "`"),
 		Example: heredoc.Doc(`
 			# Select what host and account to switch to via a prompt
@@ -62,14 +94,18 @@ func NewCmdSwitch(f *cmdutil.Factory, runF func(*SwitchOptions) error) *cobra.Co
 
 			# Switch to a specific host and specific account
 			$ gh auth switch --hostname enterprise.internal --user monalisa
+
+			# Switch based on the current directory's path or git remote
+			$ gh auth switch --auto
 		`),
 		RunE: func(c *cobra.Command, args []string) error {
 			if runF != nil {
 				return runF(&opts)
 			}
 			opts.gitConfigure = &shared.GitCredentialFlow{
-				Executable: f.Executable(),
-				GitClient:  f.GitClient,
+				Executable:      f.Executable(),
+				GitClient:       f.GitClient,
+				UseSSHConfigDir: opts.GitSSHConfig,
 			}
 
 			if runF != nil {
@@ -84,6 +120,18 @@ func NewCmdSwitch(f *cmdutil.Factory, runF func(*SwitchOptions) error) *cobra.Co
 	cmd.Flags().StringVarP(&opts.Username, "user", "u", "", "The account to switch to")
 	cmd.Flags().BoolVarP(&opts.SwitchGitLocalConfig, "git-local-config", "l", false, "Switch the local git configuration to the selected account")
 	cmd.Flags().BoolVarP(&opts.SwitchGitGlobalConfig, "git-global-config", "g", false, "Switch the global git configuration to the selected account")
+	cmd.Flags().BoolVar(&opts.SwitchSSHKey, "switch-ssh-key", false, "Switch the SSH identity used for the selected account, if it has one configured")
+	cmd.Flags().BoolVar(&opts.GitSSHConfig, "git-ssh-config", false, "Update the SSH identity in ~/.ssh/config instead of the repo-local core.sshCommand")
+	cmd.Flags().BoolVar(&opts.SetupGitCredential, "setup-git-credential", false, "Point git's credential helper at the newly active account for this host, in the global git config")
+	cmd.Flags().BoolVar(&opts.SetupGitCredentialLocal, "setup-git-credential-local", false, "With --setup-git-credential, scope the credential helper to the repo-local git config instead of global")
+	cmd.Flags().BoolVar(&opts.Auto, "auto", false, "Switch to the account matched by the current directory's path or git remote")
+	cmd.Flags().BoolVar(&opts.RespectPin, "respect-pin", false, "Switch to this repository's pinned account, if one is configured")
+
+	cmd.MarkFlagsMutuallyExclusive("auto", "respect-pin")
+
+	cmd.AddCommand(newCmdSwitchAuto(f))
+	cmd.AddCommand(newCmdSwitchPin(f))
+	cmd.AddCommand(newCmdSwitchUnpin(f))
 
 	return cmd
 }
@@ -111,6 +159,38 @@ func switchRun(opts *SwitchOptions) error {
 		return fmt.Errorf("not logged in to any hosts")
 	}
 
+	if opts.Auto {
+		rules, err := LoadAutoSwitchRules(cfg)
+		if err != nil {
+			return err
+		}
+
+		target, err := ResolveAutoSwitchTarget(opts.GitClient, rules)
+		if err != nil {
+			return err
+		}
+		if target == nil {
+			return errors.New("no auto_switch rule matched the current directory; configure one with `gh auth switch auto add`")
+		}
+
+		hostname = target.Hostname
+		username = target.User
+	}
+
+	if pin, err := ResolvePinnedAccount(opts.GitClient); err != nil {
+		return err
+	} else if pin != nil {
+		active, activeErr := authCfg.ActiveUser(pin.Hostname)
+		switch {
+		case opts.RespectPin:
+			hostname = pin.Hostname
+			username = pin.User
+		case activeErr == nil && active != pin.User && !opts.Auto && hostname == "" && username == "":
+			fmt.Fprintf(opts.IO.ErrOut, "%s this repository is pinned to %s on %s, but the active account is %s; pass --respect-pin to switch automatically\n",
+				opts.IO.ColorScheme().WarningIcon(), pin.User, pin.Hostname, active)
+		}
+	}
+
 	if hostname != "" {
 		if !slices.Contains(knownHosts, hostname) {
 			return fmt.Errorf("not logged in to %s", hostname)
@@ -212,6 +292,25 @@ func switchRun(opts *SwitchOptions) error {
 		}
 	}
 
+	if opts.SwitchSSHKey {
+		keyPath, err := authCfg.SSHKeyPath(hostname)
+		if err != nil {
+			return err
+		}
+		if keyPath == "" {
+			fmt.Fprintf(opts.IO.ErrOut, "%s account %s has no SSH key on file; skipping --switch-ssh-key\n",
+				cs.WarningIcon(), cs.Bold(username))
+		} else if err := opts.gitConfigure.SwitchSSHIdentity(hostname, keyPath); err != nil {
+			return err
+		}
+	}
+
+	if opts.SetupGitCredential {
+		if err := opts.gitConfigure.ConfigureCredentialHelper(hostname, !opts.SetupGitCredentialLocal); err != nil {
+			return err
+		}
+	}
+
 	fmt.Fprintf(opts.IO.ErrOut, "%s Switched active account for %s to %s\n",
 		cs.SuccessIcon(), hostname, cs.Bold(username))
 